@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/cjey/debpkg"
+	"github.com/cjey/debpkg/debfile"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "info":
+			cmdInfo(os.Args[2:])
+			return
+		case "control":
+			cmdControl(os.Args[2:])
+			return
+		case "contents":
+			cmdContents(os.Args[2:])
+			return
+		case "extract":
+			cmdExtract(os.Args[2:])
+			return
+		case "verify-gpg":
+			cmdVerifyGPG(os.Args[2:])
+			return
+		}
+	}
+	cmdBuild(os.Args[1:])
+}
+
+// cmdBuild builds a .deb from a debpkg.yml specfile, the original behaviour
+// of this command before the info/contents/extract/control/verify-gpg
+// subcommands were added.
+func cmdBuild(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	configFile := fs.String("c", "debpkg.yml", "YAML configuration file")
+	outputFile := fs.String("o", "", "Debian output file")
+	versionNumber := fs.String("v", os.Getenv("DEBPKG_VERSION"),
+		"Package version number (or via DEBPKG_VERSION environment variable)")
+	fs.Parse(args)
+
+	deb := debpkg.New()
+	if err := deb.Config(*configFile); err != nil {
+		log.Fatalf("Error while loading config file: %v", err)
+	}
+	if *versionNumber != "" {
+		deb.SetVersion(*versionNumber)
+	}
+	if err := deb.Write(*outputFile); err != nil {
+		log.Fatalf("Error writing outputfile: %v", err)
+	}
+	fmt.Println("debpkg: written:", *outputFile)
+}
+
+// openDebfile parses fs against args and opens the .deb/.ipk given as its
+// first non-flag argument.
+func openDebfile(fs *flag.FlagSet, args []string) *debfile.File {
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		log.Fatalf("usage: debpkg %s [flags] <file.deb>", fs.Name())
+	}
+	f, err := debfile.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("debpkg: %v", err)
+	}
+	return f
+}
+
+// cmdInfo prints the control file, equivalent to `dpkg --info`.
+func cmdInfo(args []string) {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	f := openDebfile(fs, args)
+	defer f.Close()
+
+	control, err := f.ControlFile()
+	if err != nil {
+		log.Fatalf("debpkg: %v", err)
+	}
+	fmt.Print(control)
+}
+
+// cmdControl is an alias of cmdInfo, mirroring `dpkg --control`.
+func cmdControl(args []string) {
+	cmdInfo(args)
+}
+
+// cmdContents prints a tar-style listing of the data archive, equivalent to
+// `dpkg --contents`.
+func cmdContents(args []string) {
+	fs := flag.NewFlagSet("contents", flag.ExitOnError)
+	f := openDebfile(fs, args)
+	defer f.Close()
+
+	entries, err := f.Contents()
+	if err != nil {
+		log.Fatalf("debpkg: %v", err)
+	}
+	for _, entry := range entries {
+		fmt.Println(entry)
+	}
+}
+
+// cmdExtract extracts the data archive to a directory, equivalent to
+// `dpkg --extract`.
+func cmdExtract(args []string) {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	dir := fs.String("d", ".", "directory to extract the package contents into")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		log.Fatalf("usage: debpkg extract [-d dir] <file.deb>")
+	}
+
+	f, err := debfile.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("debpkg: %v", err)
+	}
+	defer f.Close()
+
+	if err := f.ExtractTo(*dir); err != nil {
+		log.Fatalf("debpkg: %v", err)
+	}
+}
+
+// cmdVerifyGPG validates a _gpgorigin/_gpgbuilder/digests.asc clearsigned
+// member against a supplied keyring.
+func cmdVerifyGPG(args []string) {
+	fs := flag.NewFlagSet("verify-gpg", flag.ExitOnError)
+	keyring := fs.String("keyring", "", "armored GPG keyring to verify the package signature against")
+	fs.Parse(args)
+	if fs.NArg() < 1 || *keyring == "" {
+		log.Fatalf("usage: debpkg verify-gpg -keyring <keyring.gpg> <file.deb>")
+	}
+
+	f, err := debfile.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("debpkg: %v", err)
+	}
+	defer f.Close()
+
+	if err := f.VerifyGPG(*keyring); err != nil {
+		log.Fatalf("debpkg: gpg verification failed: %v", err)
+	}
+	fmt.Println("debpkg: gpg signature OK")
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import "github.com/cjey/debpkg/internal/targzip"
+
+// Compression selects the codec used to compress control.tar and data.tar
+// inside the package, see SetCompression.
+type Compression targzip.Compression
+
+const (
+	// CompressionGzip compresses with gzip. This is the default, matching
+	// dpkg-deb's historical output.
+	CompressionGzip = Compression(targzip.CompressionGzip)
+	// CompressionXz compresses with xz.
+	CompressionXz = Compression(targzip.CompressionXz)
+	// CompressionZstd compresses with Zstandard.
+	CompressionZstd = Compression(targzip.CompressionZstd)
+	// CompressionNone stores control.tar/data.tar uncompressed.
+	CompressionNone = Compression(targzip.CompressionNone)
+)
+
+// SetCompression sets the codec used to compress control.tar and data.tar
+// (default CompressionGzip). The ar(1) member filenames reflect the chosen
+// codec, e.g. "data.tar.zst" for CompressionZstd. It must be called before
+// any file is added via AddFile/AddFileString/AddDirectory.
+func (deb *DebPkg) SetCompression(c Compression) {
+	deb.control.tgz.SetCompression(targzip.Compression(c), deb.compressionLevel)
+	deb.data.tgz.SetCompression(targzip.Compression(c), deb.compressionLevel)
+}
+
+// SetCompressionLevel sets the level passed to the chosen Compression codec.
+// A level of 0 (the default) uses the codec's own default level. It must be
+// called before any file is added via AddFile/AddFileString/AddDirectory.
+func (deb *DebPkg) SetCompressionLevel(level int) {
+	deb.compressionLevel = level
+	deb.control.tgz.SetCompression(deb.control.tgz.Compression(), level)
+	deb.data.tgz.SetCompression(deb.data.tgz.Compression(), level)
+}
+
+// controlTarMemberName returns the ar(1) member filename for the control
+// tarball, e.g. "control.tar.gz" or "control.tar" for CompressionNone.
+func controlTarMemberName(deb *DebPkg) string {
+	return tarMemberName("control.tar", deb.control.tgz.Compression())
+}
+
+// dataTarMemberName returns the ar(1) member filename for the data tarball,
+// e.g. "data.tar.zst" or "data.tar" for CompressionNone.
+func dataTarMemberName(deb *DebPkg) string {
+	return tarMemberName("data.tar", deb.data.tgz.Compression())
+}
+
+func tarMemberName(base string, c targzip.Compression) string {
+	if ext := c.Extension(); ext != "" {
+		return base + "." + ext
+	}
+	return base
+}
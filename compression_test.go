@@ -0,0 +1,85 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cjey/debpkg/internal/test"
+)
+
+// Test that SetCompression changes the ar(1) member filenames and that the
+// resulting package can still be written.
+func TestWriteCompressionZstd(t *testing.T) {
+	deb := New()
+	defer deb.Close()
+
+	deb.SetName("compression-test")
+	deb.SetVersion("1.0.0")
+	deb.SetArchitecture("amd64")
+	deb.SetMaintainer("Debpkg Authors")
+	deb.SetMaintainerEmail("debpkg-authors@xor-gate.org")
+	deb.SetShortDescription("compression test package")
+	deb.SetCompression(CompressionZstd)
+
+	if got := controlTarMemberName(deb); got != "control.tar.zst" {
+		t.Errorf("controlTarMemberName() = %q, want %q", got, "control.tar.zst")
+	}
+	if got := dataTarMemberName(deb); got != "data.tar.zst" {
+		t.Errorf("dataTarMemberName() = %q, want %q", got, "data.tar.zst")
+	}
+
+	srcFile, err := test.WriteTempFile(t.Name()+".txt", "hello zstd\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deb.AddFile(srcFile, "usr/share/compression-test/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	debFilename := test.TempFile(t)
+	if err := deb.Write(debFilename); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// benchmarkCompression writes a package of about 1.4MiB of compressible data
+// with the given Compression and reports the resulting file size.
+func benchmarkCompression(b *testing.B, compression Compression) {
+	payload := strings.Repeat("the quick brown fox jumps over the lazy dog\n", 1<<15)
+
+	for i := 0; i < b.N; i++ {
+		deb := New()
+		deb.SetName("benchmark")
+		deb.SetVersion("1.0.0")
+		deb.SetArchitecture("amd64")
+		deb.SetShortDescription("benchmark package")
+		deb.SetCompression(compression)
+
+		if err := deb.AddFileString(payload, "usr/share/benchmark/payload.txt"); err != nil {
+			b.Fatal(err)
+		}
+
+		debFilename := test.TempDir() + "/" + b.Name() + ".deb"
+		if err := deb.Write(debFilename); err != nil {
+			b.Fatal(err)
+		}
+		fi, err := os.Stat(debFilename)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(float64(fi.Size()), "bytes/pkg")
+	}
+}
+
+func BenchmarkCompressionGzip(b *testing.B) {
+	benchmarkCompression(b, CompressionGzip)
+}
+
+func BenchmarkCompressionZstd(b *testing.B) {
+	benchmarkCompression(b, CompressionZstd)
+}
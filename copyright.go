@@ -0,0 +1,135 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Copyright builds a debian/copyright file conforming to the DEP-5
+// Format 1.0 machine-readable specification, installed by Write as
+// /usr/share/doc/<package>/copyright.
+// See: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+type Copyright struct {
+	deb             *DebPkg
+	upstreamName    string
+	upstreamContact string
+	source          string
+	files           []copyrightFiles
+	licenses        []copyrightLicense
+}
+
+// copyrightFiles is a single "Files" paragraph
+type copyrightFiles struct {
+	globs     []string
+	copyright string
+	license   string
+}
+
+// copyrightLicense is a single standalone "License" paragraph
+type copyrightLicense struct {
+	shortName string
+	fullText  string
+}
+
+// Copyright returns the package's Copyright builder, creating it on first use.
+func (deb *DebPkg) Copyright() *Copyright {
+	if deb.copyright == nil {
+		deb.copyright = &Copyright{deb: deb}
+	}
+	return deb.copyright
+}
+
+// SetUpstreamName sets the DEP-5 Upstream-Name field
+func (c *Copyright) SetUpstreamName(name string) *Copyright {
+	c.upstreamName = name
+	return c
+}
+
+// SetUpstreamContact sets the DEP-5 Upstream-Contact field
+func (c *Copyright) SetUpstreamContact(contact string) *Copyright {
+	c.upstreamContact = contact
+	return c
+}
+
+// SetSource sets the DEP-5 Source field, typically the upstream download URL
+func (c *Copyright) SetSource(source string) *Copyright {
+	c.source = source
+	return c
+}
+
+// AddFilesParagraph adds a "Files" paragraph covering the given glob patterns
+// (e.g. "*", "src/*.c") with the stated copyright notice and license short name.
+func (c *Copyright) AddFilesParagraph(globs []string, copyright, license string) *Copyright {
+	c.files = append(c.files, copyrightFiles{globs, copyright, license})
+	return c
+}
+
+// AddLicense adds a standalone License paragraph with the full license text,
+// referenced by shortName from the License field of a Files paragraph.
+func (c *Copyright) AddLicense(shortName, fullText string) *Copyright {
+	c.licenses = append(c.licenses, copyrightLicense{shortName, fullText})
+	return c
+}
+
+// wrapLicenseText indents a block of free text per the DEP-5 continuation
+// rules: every line gets a single leading space, and a blank line is written
+// as a lone "." -- the same convention used for the long Description field.
+func wrapLicenseText(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = " ."
+		} else {
+			lines[i] = " " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String renders the debian/copyright file in DEP-5 Format 1.0: the
+// paragraph-based RFC822-style header, followed by the Files/Copyright/
+// License paragraphs in the order they were added, followed by the
+// standalone License paragraphs.
+func (c *Copyright) String() string {
+	var o string
+
+	o += "Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/\n"
+	if c.upstreamName != "" {
+		o += fmt.Sprintf("Upstream-Name: %s\n", c.upstreamName)
+	}
+	if c.upstreamContact != "" {
+		o += fmt.Sprintf("Upstream-Contact: %s\n", c.upstreamContact)
+	}
+	if c.source != "" {
+		o += fmt.Sprintf("Source: %s\n", c.source)
+	}
+
+	for _, f := range c.files {
+		o += "\n"
+		o += fmt.Sprintf("Files: %s\n", strings.Join(f.globs, " "))
+		o += fmt.Sprintf("Copyright: %s\n", f.copyright)
+		o += fmt.Sprintf("License: %s\n", f.license)
+	}
+
+	for _, l := range c.licenses {
+		o += "\n"
+		o += fmt.Sprintf("License: %s\n", l.shortName)
+		o += wrapLicenseText(l.fullText) + "\n"
+	}
+
+	return o
+}
+
+// writeCopyrightFile installs the rendered debian/copyright file into the
+// data archive at /usr/share/doc/<package>/copyright, if Copyright() was used.
+func (deb *DebPkg) writeCopyrightFile() error {
+	if deb.copyright == nil {
+		return nil
+	}
+	dest := fmt.Sprintf("%s/%s/doc/%s/copyright", DefaultInstallPrefix, DefaultDataRootDir, deb.control.info.name)
+	return deb.AddFileString(deb.copyright.String(), dest)
+}
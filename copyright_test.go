@@ -0,0 +1,44 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Test correct rendering of a DEP-5 debian/copyright file with a Files
+// paragraph and a standalone License paragraph with wrapped full text
+func TestCopyrightFileDEP5(t *testing.T) {
+	copyrightExpect := `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: foobar
+Upstream-Contact: Jerry Jacobs <foo@bar.com>
+Source: https://github.com/cjey/debpkg
+
+Files: *
+Copyright: 2017 Debpkg authors
+License: MIT
+
+License: MIT
+ Permission is hereby granted, free of charge, to any person obtaining a copy.
+ .
+ THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND.
+`
+	deb := New()
+	defer deb.Close()
+
+	deb.Copyright().
+		SetUpstreamName("foobar").
+		SetUpstreamContact("Jerry Jacobs <foo@bar.com>").
+		SetSource("https://github.com/cjey/debpkg").
+		AddFilesParagraph([]string{"*"}, "2017 Debpkg authors", "MIT").
+		AddLicense("MIT", "Permission is hereby granted, free of charge, to any person obtaining a copy.\n\nTHE SOFTWARE IS PROVIDED \"AS IS\", WITHOUT WARRANTY OF ANY KIND.")
+
+	copyright := deb.copyright.String()
+	if copyright != copyrightExpect {
+		t.Error("Unexpected copyright file")
+		fmt.Printf("--- expected (len %d):\n'%s'\n--- got (len %d):\n'%s'---\n", len(copyrightExpect), copyrightExpect, len(copyright), copyright)
+	}
+}
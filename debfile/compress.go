@@ -0,0 +1,37 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// tarReader opens a decompressing reader for a "control.tar.*"/"data.tar.*"
+// ar member, picking the decompressor from its file extension.
+func tarReader(memberName string, body []byte) (io.Reader, error) {
+	switch {
+	case strings.HasSuffix(memberName, ".tar"):
+		return bytes.NewReader(body), nil
+	case strings.HasSuffix(memberName, ".tar.gz"):
+		return gzip.NewReader(bytes.NewReader(body))
+	case strings.HasSuffix(memberName, ".tar.xz"):
+		return xz.NewReader(bytes.NewReader(body))
+	case strings.HasSuffix(memberName, ".tar.zst"):
+		dec, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression for member %q", memberName)
+	}
+}
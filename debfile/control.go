@@ -0,0 +1,77 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debfile
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// ControlFile returns the raw contents of the "control" member inside
+// control.tar.*, equivalent to `dpkg --control`/`dpkg --info`.
+func (f *File) ControlFile() (string, error) {
+	name, body, _ := f.controlMember()
+
+	tr, err := tarReader(name, body)
+	if err != nil {
+		return "", fmt.Errorf("cannot decompress %s: %v", name, err)
+	}
+
+	t := tar.NewReader(tr)
+	for {
+		hdr, err := t.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("cannot read %s: %v", name, err)
+		}
+		if strings.TrimPrefix(hdr.Name, "./") != "control" {
+			continue
+		}
+		b, err := ioutil.ReadAll(t)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	return "", fmt.Errorf("%s: no control file found", name)
+}
+
+// Info parses the control file into its RFC822-style fields, equivalent to
+// the summary `dpkg --info` prints. Continuation lines (starting with a
+// single space, as used by the long Description field) are appended to the
+// value of the preceding field.
+func (f *File) Info() (map[string]string, error) {
+	control, err := f.ControlFile()
+	if err != nil {
+		return nil, err
+	}
+
+	info := make(map[string]string)
+	var lastKey string
+
+	for _, line := range strings.Split(control, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, " ") && lastKey != "" {
+			info[lastKey] += "\n" + line
+			continue
+		}
+		key, value, found := strings.Cut(line, ": ")
+		if !found {
+			continue
+		}
+		info[key] = value
+		lastKey = key
+	}
+
+	return info, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debfile
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Contents returns a tar-style listing of the data.tar.* payload, one line
+// per entry, equivalent to `dpkg --contents`:
+//  drwxr-xr-x root/root         0 2021-01-01 00:00 ./usr/bin/
+//  -rwxr-xr-x root/root      1234 2021-01-01 00:00 ./usr/bin/foo
+func (f *File) Contents() ([]string, error) {
+	name, body, _ := f.dataMember()
+
+	tr, err := tarReader(name, body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress %s: %v", name, err)
+	}
+
+	var lines []string
+	t := tar.NewReader(tr)
+	for {
+		hdr, err := t.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %v", name, err)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s/%s %9d %s %s",
+			hdr.FileInfo().Mode(),
+			hdr.Uname, hdr.Gname,
+			hdr.Size,
+			hdr.ModTime.Format("2006-01-02 15:04"),
+			hdr.Name))
+	}
+
+	return lines, nil
+}
+
+// ExtractTo extracts every entry of the data.tar.* payload into dir,
+// equivalent to `dpkg --extract`. dir is created if it does not exist.
+func (f *File) ExtractTo(dir string) error {
+	name, body, _ := f.dataMember()
+
+	tr, err := tarReader(name, body)
+	if err != nil {
+		return fmt.Errorf("cannot decompress %s: %v", name, err)
+	}
+
+	dir = filepath.Clean(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	t := tar.NewReader(tr)
+	for {
+		hdr, err := t.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %v", name, err)
+		}
+
+		dest, err := sanitizeExtractPath(dir, hdr.Name)
+		if err != nil {
+			return fmt.Errorf("cannot extract %s: %v", hdr.Name, err)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dest, os.FileMode(hdr.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return err
+			}
+			fd, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(hdr.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(fd, t); err != nil {
+				fd.Close()
+				return err
+			}
+			if err := fd.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if filepath.IsAbs(hdr.Linkname) {
+				return fmt.Errorf("cannot extract %s: symlink target %q is absolute", hdr.Name, hdr.Linkname)
+			}
+			if _, err := sanitizeExtractPath(dir, filepath.Join(filepath.Dir(hdr.Name), hdr.Linkname)); err != nil {
+				return fmt.Errorf("cannot extract %s: symlink target escapes %s: %v", hdr.Name, dir, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(hdr.Linkname, dest); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sanitizeExtractPath joins dir and name, the way ExtractTo does for every
+// tar entry, and rejects the result if it escapes dir (a "Zip-Slip" entry
+// such as "../../etc/passwd" or an absolute path). Since ExtractTo exists to
+// open arbitrary, potentially untrusted .deb/.ipk files, every entry must be
+// contained within dir before anything is written.
+func sanitizeExtractPath(dir, name string) (string, error) {
+	dest := filepath.Join(dir, name)
+	if dest != dir && !strings.HasPrefix(dest, dir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("%q escapes extract directory %q", name, dir)
+	}
+	return dest, nil
+}
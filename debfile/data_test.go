@@ -0,0 +1,76 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debfile
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+
+	"github.com/cjey/debpkg/internal/test"
+)
+
+// tarGz builds a gzip-compressed tar archive from name/body pairs, for
+// crafting data.tar.gz members by hand in tests.
+func tarGz(entries map[string]string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, body := range entries {
+		tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(body)), Mode: 0644, Typeflag: tar.TypeReg})
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
+
+// Test that a data.tar.gz member crafted with a path-traversal entry name
+// ("Zip-Slip") is rejected by ExtractTo instead of being written outside
+// the requested extract directory.
+func TestExtractToRejectsZipSlip(t *testing.T) {
+	evilPath := "/tmp/evil_zipslip_poc.txt"
+	os.Remove(evilPath)
+
+	f := &File{members: map[string][]byte{
+		"control.tar.gz": tarGz(map[string]string{"control": "Package: evil\n"}),
+		"data.tar.gz":    tarGz(map[string]string{"../../../../../../../../../../../../tmp/evil_zipslip_poc.txt": "pwned\n"}),
+	}}
+	defer f.Close()
+
+	extractDir := test.TempDir() + "/" + t.Name() + "-extract"
+	if err := f.ExtractTo(extractDir); err == nil {
+		t.Fatal("ExtractTo did not reject a path-traversal entry")
+	}
+
+	if _, err := os.Stat(evilPath); err == nil {
+		t.Errorf("ExtractTo wrote outside the extract directory: %s", evilPath)
+	}
+}
+
+// Test that a symlink entry whose target escapes the extract directory is
+// rejected, instead of creating a symlink that could be used to write
+// outside dir via a later entry.
+func TestExtractToRejectsSymlinkEscape(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../../../../../../../tmp"})
+	tw.Close()
+	gw.Close()
+
+	f := &File{members: map[string][]byte{
+		"control.tar.gz": tarGz(map[string]string{"control": "Package: evil\n"}),
+		"data.tar.gz":    buf.Bytes(),
+	}}
+	defer f.Close()
+
+	extractDir := test.TempDir() + "/" + t.Name() + "-extract"
+	if err := f.ExtractTo(extractDir); err == nil {
+		t.Fatal("ExtractTo did not reject a symlink escaping the extract directory")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debfile
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/xor-gate/ar"
+)
+
+// File is an opened .deb/.ipk archive, ready for inspection.
+type File struct {
+	members map[string][]byte // ar member name -> raw bytes
+}
+
+// Open reads the ar(1) archive at filename into memory and returns a File
+// ready for inspection. The archive itself is small (an ar index plus a
+// handful of tar.gz/tar.xz/tar.zst members); its members are buffered so
+// Info/Contents/ExtractTo/VerifyGPG can be called in any order.
+func Open(filename string) (*File, error) {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	f := &File{members: make(map[string][]byte)}
+
+	r := ar.NewReader(fd)
+	for {
+		hdr, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read ar archive: %v", err)
+		}
+		body, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read ar member %q: %v", hdr.Name, err)
+		}
+		f.members[strings.TrimSpace(hdr.Name)] = body
+	}
+
+	if _, _, ok := f.controlMember(); !ok {
+		return nil, fmt.Errorf("%s: no control.tar.* member found", filename)
+	}
+	if _, _, ok := f.dataMember(); !ok {
+		return nil, fmt.Errorf("%s: no data.tar.* member found", filename)
+	}
+
+	return f, nil
+}
+
+// Close releases the in-memory archive. File does not hold any open file
+// descriptors after Open returns, so Close never fails.
+func (f *File) Close() error {
+	f.members = nil
+	return nil
+}
+
+// memberWithPrefix returns the name and bytes of the first buffered ar
+// member whose name starts with prefix, e.g. "control.tar" matches
+// "control.tar.gz", "control.tar.xz" and "control.tar.zst".
+func (f *File) memberWithPrefix(prefix string) (name string, body []byte, ok bool) {
+	for name, body := range f.members {
+		if strings.HasPrefix(name, prefix) {
+			return name, body, true
+		}
+	}
+	return "", nil, false
+}
+
+func (f *File) controlMember() (string, []byte, bool) { return f.memberWithPrefix("control.tar") }
+func (f *File) dataMember() (string, []byte, bool)     { return f.memberWithPrefix("data.tar") }
+
+// gpgMember returns the first present clearsigned signature member, trying
+// the dpkg names in the order dpkg-sig/debsigs use them.
+func (f *File) gpgMember() (name string, body []byte, ok bool) {
+	for _, name := range []string{"_gpgorigin", "_gpgbuilder", "digests.asc"} {
+		if body, ok := f.members[name]; ok {
+			return name, body, true
+		}
+	}
+	return "", nil, false
+}
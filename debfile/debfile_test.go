@@ -0,0 +1,84 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debfile
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/cjey/debpkg"
+	"github.com/cjey/debpkg/internal/test"
+)
+
+// Test that a .deb written by debpkg can be read back: Info() reports the
+// control fields, Contents() lists the added file and ExtractTo() restores it
+func TestOpenInfoContentsExtract(t *testing.T) {
+	deb := debpkg.New()
+	defer deb.Close()
+
+	deb.SetName("debfile-test")
+	deb.SetVersion("1.2.3")
+	deb.SetArchitecture("amd64")
+	deb.SetMaintainer("Debpkg Authors")
+	deb.SetMaintainerEmail("debpkg-authors@xor-gate.org")
+	deb.SetShortDescription("debfile round-trip test package")
+
+	srcFile, err := test.WriteTempFile(t.Name()+".txt", "hello world\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deb.AddFile(srcFile, "usr/share/debfile-test/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	debFilename := test.TempFile(t)
+	if err := deb.Write(debFilename); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Open(debFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	info, err := f.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info["Package"] != "debfile-test" {
+		t.Errorf("Info()[\"Package\"] = %q, want %q", info["Package"], "debfile-test")
+	}
+	if info["Version"] != "1.2.3" {
+		t.Errorf("Info()[\"Version\"] = %q, want %q", info["Version"], "1.2.3")
+	}
+
+	contents, err := f.Contents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, line := range contents {
+		if strings.Contains(line, "usr/share/debfile-test/hello.txt") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Contents() did not list the added file: %v", contents)
+	}
+
+	extractDir := test.TempDir() + "/" + t.Name() + "-extract"
+	if err := f.ExtractTo(extractDir); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(extractDir + "/usr/share/debfile-test/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello world\n" {
+		t.Errorf("extracted file content = %q, want %q", string(b), "hello world\n")
+	}
+}
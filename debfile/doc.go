@@ -0,0 +1,10 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package debfile implements read access to existing .deb (and .ipk)
+// archives: the outer ar(1) container and the inner control.tar.* and
+// data.tar.* members (gzip, xz and zstd compressed), plus verification of
+// a clearsigned _gpgorigin/_gpgbuilder/digests.asc member. It mirrors the
+// subset of dpkg --info/--contents/--extract/--control used by cmd/debpkg.
+package debfile
@@ -0,0 +1,46 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+// VerifyGPG validates the package's clearsigned digest member (tried in the
+// order "_gpgorigin", "_gpgbuilder", "digests.asc") against the public keys
+// found in the armored keyringFile.
+func (f *File) VerifyGPG(keyringFile string) error {
+	memberName, body, ok := f.gpgMember()
+	if !ok {
+		return fmt.Errorf("no gpg signature member found (_gpgorigin, _gpgbuilder or digests.asc)")
+	}
+
+	kr, err := os.Open(keyringFile)
+	if err != nil {
+		return err
+	}
+	defer kr.Close()
+
+	keyring, err := openpgp.ReadArmoredKeyRing(kr)
+	if err != nil {
+		return fmt.Errorf("cannot read keyring: %v", err)
+	}
+
+	block, _ := clearsign.Decode(body)
+	if block == nil {
+		return fmt.Errorf("%s is not a valid clearsigned message", memberName)
+	}
+
+	if _, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body); err != nil {
+		return fmt.Errorf("signature verification of %s failed: %v", memberName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"go/build"
+)
+
+// GetArchitecture gets the current build.Default.GOARCH in debian-form
+func GetArchitecture() string {
+	arch := build.Default.GOARCH
+	if arch == "386" {
+		return "i386"
+	}
+	return arch
+}
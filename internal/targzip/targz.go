@@ -0,0 +1,359 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package targzip
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compression selects the codec used to compress a TarGzip's tar stream.
+type Compression int
+
+const (
+	// CompressionGzip compresses with gzip. This is the default, matching
+	// the historical behaviour of this package and of dpkg-deb.
+	CompressionGzip Compression = iota
+	// CompressionXz compresses with xz.
+	CompressionXz
+	// CompressionZstd compresses with Zstandard.
+	CompressionZstd
+	// CompressionNone stores the tar stream uncompressed.
+	CompressionNone
+)
+
+// Extension returns the ar(1) member filename extension (without a leading
+// dot, e.g. "gz") used for a tarball compressed with c, or "" for
+// CompressionNone.
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionXz:
+		return "xz"
+	case CompressionZstd:
+		return "zst"
+	case CompressionNone:
+		return ""
+	default:
+		return "gz"
+	}
+}
+
+// tarEntry buffers one AddFile/AddFileFromBuffer/AddDirectory call so that
+// Close can write every entry in lexical name order, for reproducible
+// output regardless of call order.
+type tarEntry struct {
+	hdr  tar.Header
+	body []byte
+}
+
+// TarGzip is a combined writer for .tar[.gz|.xz|.zst]-alike files. Entries
+// are buffered and written in lexical name order on Close, so that the
+// resulting tarball does not depend on the order AddFile et al. were called
+// in. The compressor and tar.Writer are created lazily on first write, so
+// SetCompression can still change the codec up until then.
+type TarGzip struct {
+	file        *os.File
+	cw          io.WriteCloser // compressor wrapping file, chosen by compression
+	tw          *tar.Writer
+	entries     []tarEntry
+	written     uint64
+	fileName    string
+	compression Compression
+	level       int
+	modTime     time.Time
+	hasModTime  bool
+}
+
+// NewTempFile create a new targzip writer tempfile. It defaults to
+// CompressionGzip; use SetCompression to change the codec before Close.
+func NewTempFile(dir string) (*TarGzip, error) {
+	f, err := ioutil.TempFile(dir, "debpkg")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TarGzip{file: f, fileName: f.Name()}, nil
+}
+
+// SetCompression selects the codec (and, for codecs that support it, the
+// compression level; 0 means "use the codec's own default") used to
+// compress the tar stream. It must be called before Close, since the
+// compressor is created lazily at that point.
+func (t *TarGzip) SetCompression(compression Compression, level int) {
+	t.compression = compression
+	t.level = level
+}
+
+// Compression returns the codec this writer was configured with.
+func (t *TarGzip) Compression() Compression {
+	return t.compression
+}
+
+// Level returns the compression level this writer was configured with.
+func (t *TarGzip) Level() int {
+	return t.level
+}
+
+// SetModTime overrides the modification time recorded for every entry
+// (directory, file-from-buffer, or on-disk file, regardless of its own
+// mtime) added after this call, for reproducible output independent of
+// wall-clock time. Entries added before the call keep their own time.
+func (t *TarGzip) SetModTime(modTime time.Time) {
+	t.modTime = modTime
+	t.hasModTime = true
+}
+
+// applyDeterministicMetadata forces ownership to root/root and, if
+// SetModTime was called, the entry's timestamp, so that a tarball's bytes
+// depend only on its content and the configured build time.
+func (t *TarGzip) applyDeterministicMetadata(hdr *tar.Header) {
+	hdr.Uid = 0
+	hdr.Gid = 0
+	hdr.Uname = "root"
+	hdr.Gname = "root"
+	if t.hasModTime {
+		hdr.ModTime = t.modTime
+	}
+}
+
+// init lazily creates the compressor and tar.Writer for the configured
+// Compression, the first time entries are written out in Close.
+func (t *TarGzip) init() error {
+	if t.tw != nil {
+		return nil
+	}
+
+	switch t.compression {
+	case CompressionXz:
+		w, err := xz.NewWriter(t.file)
+		if err != nil {
+			return fmt.Errorf("cannot create xz writer: %v", err)
+		}
+		t.cw = w
+	case CompressionZstd:
+		w, err := zstd.NewWriter(t.file, zstd.WithEncoderLevel(zstdLevel(t.level)))
+		if err != nil {
+			return fmt.Errorf("cannot create zstd writer: %v", err)
+		}
+		t.cw = w
+	case CompressionNone:
+		t.cw = nopWriteCloser{t.file}
+	default:
+		w, err := gzip.NewWriterLevel(t.file, gzipLevel(t.level))
+		if err != nil {
+			return fmt.Errorf("cannot create gzip writer: %v", err)
+		}
+		// Zero the mtime/OS byte of the gzip header explicitly, so the
+		// compressed bytes depend only on the tar stream, not on when or
+		// where it was built.
+		w.ModTime = time.Time{}
+		w.OS = 0
+		t.cw = w
+	}
+
+	t.tw = tar.NewWriter(t.cw)
+	return nil
+}
+
+// gzipLevel maps our 0-9 level knob (0 meaning "default") onto gzip's
+// NewWriterLevel range.
+func gzipLevel(level int) int {
+	if level <= 0 {
+		return gzip.DefaultCompression
+	}
+	if level > gzip.BestCompression {
+		return gzip.BestCompression
+	}
+	return level
+}
+
+// zstdLevel maps our 0-9 level knob (0 meaning "default") onto zstd's four
+// EncoderLevel tiers.
+func zstdLevel(level int) zstd.EncoderLevel {
+	switch {
+	case level <= 0:
+		return zstd.SpeedDefault
+	case level <= 3:
+		return zstd.SpeedFastest
+	case level <= 6:
+		return zstd.SpeedDefault
+	case level <= 8:
+		return zstd.SpeedBetterCompression
+	default:
+		return zstd.SpeedBestCompression
+	}
+}
+
+// nopWriteCloser adapts an io.Writer to io.WriteCloser with a no-op Close,
+// used for CompressionNone where the underlying file is closed separately.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// AddFile buffers a file from filename into dest, to be written out by Close.
+func (t *TarGzip) AddFile(filename string, dest ...string) error {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	stat, err := fd.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.Mode().IsDir() {
+		return nil
+	}
+
+	// now lets create the header as needed for this file within the tarball
+	hdr, err := tar.FileInfoHeader(stat, filename)
+	if err != nil {
+		return fmt.Errorf("dir tar finfo: %v", err)
+	}
+
+	if len(dest) > 0 && len(dest[0]) > 0 {
+		hdr.Name = dest[0]
+	} else {
+		hdr.Name = filename
+	}
+
+	if hdr.Name == "" {
+		return fmt.Errorf("empty destination filename")
+	}
+
+	hdr.Name = strings.Trim(hdr.Name, "/")
+
+	body, err := ioutil.ReadAll(fd)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %v", filename, err)
+	}
+	hdr.Size = int64(len(body))
+
+	t.applyDeterministicMetadata(hdr)
+	t.entries = append(t.entries, tarEntry{hdr: *hdr, body: body})
+	t.written += uint64(len(body))
+
+	return nil
+}
+
+// AddFileFromBuffer buffers a file from b, to be written out by Close.
+func (t *TarGzip) AddFileFromBuffer(filename string, b []byte) error {
+	hdr := tar.Header{
+		Name:     strings.Trim(filename, "/"),
+		Size:     int64(len(b)),
+		Mode:     0644,
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}
+	t.applyDeterministicMetadata(&hdr)
+
+	body := make([]byte, len(b))
+	copy(body, b)
+	t.entries = append(t.entries, tarEntry{hdr: hdr, body: body})
+	t.written += uint64(len(b))
+
+	return nil
+}
+
+// AddDirectory buffers a directory entry, to be written out by Close.
+func (t *TarGzip) AddDirectory(dirpath string) error {
+	dirpath = strings.Trim(dirpath, "/")
+	hdr := tar.Header{
+		Name:     dirpath,
+		Mode:     int64(0755 | 040000),
+		Typeflag: tar.TypeDir,
+		ModTime:  time.Now(),
+		Size:     0,
+	}
+	t.applyDeterministicMetadata(&hdr)
+
+	t.entries = append(t.entries, tarEntry{hdr: hdr})
+	return nil
+}
+
+// Written returns the amount of bytes written in uncompressed form
+func (t *TarGzip) Written() uint64 {
+	return t.written
+}
+
+// Close writes every buffered entry, sorted lexically by name, then closes
+// the tar.Writer and compressor. It may be called more than once, which
+// re-renders the file from scratch under whatever Compression is currently
+// configured; this lets the same buffered entries back both deb.Write and
+// deb.WriteIPK, which each want their own codec for the same payload.
+func (t *TarGzip) Close() error {
+	if t.tw != nil {
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := t.file.Truncate(0); err != nil {
+			return err
+		}
+		t.tw = nil
+	}
+
+	if err := t.init(); err != nil {
+		return err
+	}
+
+	sort.Slice(t.entries, func(i, j int) bool {
+		return t.entries[i].hdr.Name < t.entries[j].hdr.Name
+	})
+
+	for _, e := range t.entries {
+		hdr := e.hdr
+		if err := t.tw.WriteHeader(&hdr); err != nil {
+			return err
+		}
+		if len(e.body) > 0 {
+			if _, err := t.tw.Write(e.body); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	if err := t.cw.Close(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Name returns the name of the file as presented to Open.
+func (t *TarGzip) Name() string {
+	return t.fileName
+}
+
+// Size returns the length in bytes for the closed file
+func (t *TarGzip) Size() int64 {
+	fi, err := os.Stat(t.Name())
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
+
+// Remove removes the tempfile
+func (t *TarGzip) Remove() error {
+	if t.fileName == "" {
+		return nil
+	}
+	return os.Remove(t.fileName)
+}
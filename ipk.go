@@ -0,0 +1,78 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"fmt"
+
+	"github.com/cjey/debpkg/internal/targzip"
+	"github.com/cjey/debpkg/ipk"
+)
+
+// WriteIPK writes the package in OPKG/IPK format (see github.com/cjey/debpkg/ipk),
+// reusing the data.tar.gz already built via AddFile/AddDirectory and a control
+// file rendered from the same Set* fields. The dpkg architecture name is
+// translated to its opkg Tuple equivalent (e.g. "arm64" -> "aarch64_generic")
+// with ipk.TranslateArchitecture.
+//
+// WriteIPK does not expose the opkg-only Alternatives/Auto-Installed/Essential/
+// OE-* control fields; use the ipk package directly when those are needed.
+//
+// WriteIPK does not close deb, so Write can still be called on the same
+// instance afterwards (or beforehand) to also emit a .deb alongside the
+// .ipk; call Close when done with both.
+func (deb *DebPkg) WriteIPK(filename string) error {
+	if deb.err != nil {
+		return deb.err
+	}
+	if err := deb.control.verify(); err != nil {
+		deb.setError(err)
+		return err
+	}
+
+	// opkg only understands gzip-compressed payloads, regardless of any
+	// SetCompression call made for the .deb output, so force it here before
+	// handing deb.data.tgz's on-disk tarball to the ipk writer. deb.data.tgz
+	// is shared with Write, so its original compression is restored below
+	// once the gzip rendering has been read by ipk.WriteFile.
+	origCompression, origLevel := deb.data.tgz.Compression(), deb.data.tgz.Level()
+	deb.data.tgz.SetCompression(targzip.CompressionGzip, 0)
+	defer deb.data.tgz.SetCompression(origCompression, origLevel)
+
+	if err := deb.data.tgz.Close(); err != nil {
+		err = fmt.Errorf("cannot close tgz writer: %v", err)
+		deb.setError(err)
+		return err
+	}
+
+	info := deb.Info()
+	info.Architecture = ipk.TranslateArchitecture(info.Architecture)
+
+	if filename == "" {
+		filename = fmt.Sprintf("%s_%s_%s.ipk", info.Name, info.Version, info.Architecture)
+	}
+
+	control := ipk.RenderControl(ipk.Info{
+		Name:            info.Name,
+		Version:         info.Version,
+		Architecture:    info.Architecture,
+		Maintainer:      info.Maintainer,
+		MaintainerEmail: info.MaintainerEmail,
+		Homepage:        info.Homepage,
+		Depends:         info.Depends,
+		Recommends:      info.Recommends,
+		Suggests:        info.Suggests,
+		Conflicts:       info.Conflicts,
+		Provides:        info.Provides,
+		Replaces:        info.Replaces,
+		Section:         info.Section,
+		DescrShort:      info.DescrShort,
+		Descr:           info.Descr,
+	})
+
+	err := ipk.WriteFile(filename, control, deb.data.tgz.Name())
+	deb.setError(err)
+	return err
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package ipk
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/xor-gate/ar"
+)
+
+const ipkBinaryVersion = "2.0\n"
+
+func addArFileFromBuffer(now time.Time, w *ar.Writer, name string, body []byte) error {
+	hdr := ar.Header{
+		Name:    name,
+		Size:    int64(len(body)),
+		Mode:    0644,
+		ModTime: now,
+	}
+
+	if err := w.WriteHeader(&hdr); err != nil {
+		return fmt.Errorf("cannot write file header: %v", err)
+	}
+
+	_, err := w.Write(body)
+
+	return err
+}
+
+func addArFile(now time.Time, w *ar.Writer, dstname, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr := ar.Header{
+		Name:    dstname,
+		Size:    stat.Size(),
+		Mode:    0644,
+		ModTime: now,
+	}
+
+	if err := w.WriteHeader(&hdr); err != nil {
+		return fmt.Errorf("cannot write file header: %v", err)
+	}
+
+	_, err = io.Copy(w, f)
+
+	return err
+}
+
+// createIpkAr packs debian-binary, control.tar.gz and data.tar.gz into the
+// ar(1) archive opkg expects as an .ipk file.
+func (pkg *Package) createIpkAr(filename string) error {
+	return WriteArchive(filename, pkg.control.tgz.Name(), pkg.data.tgz.Name())
+}
+
+// WriteArchive packs filename as an ar(1) IPK from an already-rendered
+// control.tar.gz and data.tar.gz found on disk at controlTarGzPath and
+// dataTarGzPath. It is exported so a sibling writer that already has a
+// finished data.tar.gz, such as debpkg.DebPkg.WriteIPK, can produce an IPK
+// without re-encoding the payload.
+func WriteArchive(filename, controlTarGzPath, dataTarGzPath string) error {
+	removeIpk := true
+	fd, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("unable to create: %s", filename)
+	}
+
+	defer func() {
+		fd.Close()
+		if removeIpk {
+			os.Remove(filename)
+		}
+	}()
+
+	now := time.Now()
+	w := ar.NewWriter(fd)
+
+	if err := w.WriteGlobalHeader(); err != nil {
+		return fmt.Errorf("cannot write ar header to ipk file: %v", err)
+	}
+	if err := addArFileFromBuffer(now, w, "debian-binary", []byte(ipkBinaryVersion)); err != nil {
+		return fmt.Errorf("cannot pack debian-binary: %v", err)
+	}
+	if err := addArFile(now, w, "control.tar.gz", controlTarGzPath); err != nil {
+		return fmt.Errorf("cannot add control.tar.gz to ipk: %v", err)
+	}
+	if err := addArFile(now, w, "data.tar.gz", dataTarGzPath); err != nil {
+		return fmt.Errorf("cannot add data.tar.gz to ipk: %v", err)
+	}
+
+	removeIpk = false
+
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package ipk
+
+// debToOpkgArch translates a dpkg-architecture (`dpkg --print-architecture`)
+// name to the Tuple most OpenWrt/Yocto opkg feeds advertise for it. Unknown
+// architectures are passed through unchanged, since many OpenWrt targets
+// (e.g. "mips_24kc") already use opkg-native names.
+var debToOpkgArch = map[string]string{
+	"amd64":   "x86_64",
+	"arm64":   "aarch64_generic",
+	"armhf":   "arm_cortex-a9",
+	"armel":   "arm_arm926ej-s",
+	"i386":    "i386_pentium4",
+	"mips":    "mips_24kc",
+	"mipsel":  "mipsel_24kc",
+	"powerpc": "powerpc_405",
+}
+
+// TranslateArchitecture maps a dpkg architecture name (e.g "amd64") to the
+// opkg Tuple name used in IPK control files and feed indexes (e.g
+// "x86_64"). Architectures without a known translation are returned
+// unchanged.
+func TranslateArchitecture(debArch string) string {
+	if opkgArch, ok := debToOpkgArch[debArch]; ok {
+		return opkgArch
+	}
+	return debArch
+}
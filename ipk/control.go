@@ -0,0 +1,232 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package ipk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cjey/debpkg/internal/targzip"
+)
+
+type control struct {
+	tgz  *targzip.TarGzip
+	info Info
+}
+
+// Info holds the IPK/OPKG control metadata. It is exported so that a sibling
+// writer which already has this metadata available, such as
+// debpkg.DebPkg.WriteIPK, can render an IPK control file without rebuilding
+// a Package from scratch.
+type Info struct {
+	Name            string
+	Version         string
+	Architecture    string
+	Maintainer      string
+	MaintainerEmail string
+	Homepage        string
+	Depends         string
+	Recommends      string
+	Suggests        string
+	Conflicts       string
+	Provides        string
+	Replaces        string
+	Section         string
+	DescrShort      string // Short package description
+	Descr           string // Long package description, pre-formatted with leading-space continuations
+
+	// opkg specific fields, see https://code.googlesource.com/opkg/+/master/doc/opkg.spec
+	Alternatives  string
+	AutoInstalled bool
+	Essential     bool
+	OEFields      map[string]string // Yocto "OE-*" fields, e.g OE-Owner, OE-Priority
+}
+
+// SetName sets the name of the package (mandatory)
+func (pkg *Package) SetName(name string) {
+	pkg.control.info.Name = name
+}
+
+// SetVersion sets the full version string (mandatory). E.g "1.2.3-r1"
+func (pkg *Package) SetVersion(version string) {
+	pkg.control.info.Version = version
+}
+
+// SetArchitecture sets the opkg architecture Tuple the package installs on,
+// e.g "x86_64" or "aarch64_generic". Use TranslateArchitecture to derive it
+// from a dpkg architecture name.
+func (pkg *Package) SetArchitecture(arch string) {
+	pkg.control.info.Architecture = arch
+}
+
+// SetMaintainer sets the package maintainers name and surname. E.g: "Foo Bar"
+func (pkg *Package) SetMaintainer(maintainer string) {
+	pkg.control.info.Maintainer = maintainer
+}
+
+// SetMaintainerEmail sets the package maintainers email address. E.g: "foo@bar.com"
+func (pkg *Package) SetMaintainerEmail(email string) {
+	pkg.control.info.MaintainerEmail = email
+}
+
+// SetHomepage sets the homepage URL of the package
+func (pkg *Package) SetHomepage(url string) {
+	pkg.control.info.Homepage = url
+}
+
+// SetDepends sets the package dependencies. E.g: "libc, libopenssl"
+func (pkg *Package) SetDepends(depends string) {
+	pkg.control.info.Depends = depends
+}
+
+// SetRecommends sets the package recommendations
+func (pkg *Package) SetRecommends(recommends string) {
+	pkg.control.info.Recommends = recommends
+}
+
+// SetSuggests sets the package suggestions
+func (pkg *Package) SetSuggests(suggests string) {
+	pkg.control.info.Suggests = suggests
+}
+
+// SetConflicts sets one or more conflicting packages
+func (pkg *Package) SetConflicts(conflicts string) {
+	pkg.control.info.Conflicts = conflicts
+}
+
+// SetProvides sets the type which the package provides
+func (pkg *Package) SetProvides(provides string) {
+	pkg.control.info.Provides = provides
+}
+
+// SetReplaces sets the names of packages which will be replaced
+func (pkg *Package) SetReplaces(replaces string) {
+	pkg.control.info.Replaces = replaces
+}
+
+// SetSection sets the feed section the package belongs to, e.g "net"
+func (pkg *Package) SetSection(section string) {
+	pkg.control.info.Section = section
+}
+
+// SetShortDescription sets the single line synopsis
+func (pkg *Package) SetShortDescription(descr string) {
+	pkg.control.info.DescrShort = descr
+}
+
+// SetDescription sets the extended description over several lines.
+// NOTE: like the dpkg control file, the opkg control file requires every
+// continuation line of a multi-line field to start with a single space.
+func (pkg *Package) SetDescription(descr string) {
+	pkg.control.info.Descr = " " + strings.Replace(descr, "\n", "\n ", -1)
+}
+
+// SetAlternatives declares an opkg "update-alternatives" provider, e.g:
+//  "100:/usr/bin/foo:/usr/bin/foo.foopkg"
+// See: https://code.googlesource.com/opkg/+/master/doc/opkg.spec
+func (pkg *Package) SetAlternatives(alternatives string) {
+	pkg.control.info.Alternatives = alternatives
+}
+
+// SetAutoInstalled marks the package as having been installed as a
+// dependency rather than explicitly requested by the user.
+func (pkg *Package) SetAutoInstalled(autoInstalled bool) {
+	pkg.control.info.AutoInstalled = autoInstalled
+}
+
+// SetEssential marks the package as essential, opkg then refuses to remove
+// it without the --force-removal-of-essential-packages flag.
+func (pkg *Package) SetEssential(essential bool) {
+	pkg.control.info.Essential = essential
+}
+
+// SetOEField sets a Yocto/OpenEmbedded "OE-*" control field, e.g:
+//  pkg.SetOEField("OE-Owner", "yoe@example.com")
+func (pkg *Package) SetOEField(name, value string) {
+	if pkg.control.info.OEFields == nil {
+		pkg.control.info.OEFields = make(map[string]string)
+	}
+	pkg.control.info.OEFields[name] = value
+}
+
+// verify the control file for validity
+func (c *control) verify() error {
+	if c.info.Name == "" {
+		return fmt.Errorf("empty package name")
+	}
+	if c.info.Architecture == "" {
+		return fmt.Errorf("empty architecture")
+	}
+	return nil
+}
+
+// RenderControl renders the IPK/OPKG control file from info. Unlike the dpkg
+// control file it carries no Installed-Size or Md5sum field, and supports
+// the Alternatives/Auto-Installed/Essential/OE-* opkg extensions.
+func RenderControl(info Info) string {
+	var o string
+
+	o += fmt.Sprintf("Package: %s\n", info.Name)
+	o += fmt.Sprintf("Version: %s\n", info.Version)
+	o += fmt.Sprintf("Architecture: %s\n", info.Architecture)
+	o += fmt.Sprintf("Maintainer: %s <%s>\n", info.Maintainer, info.MaintainerEmail)
+
+	if info.Section != "" {
+		o += fmt.Sprintf("Section: %s\n", info.Section)
+	}
+	if info.Homepage != "" {
+		o += fmt.Sprintf("Homepage: %s\n", info.Homepage)
+	}
+	if info.Depends != "" {
+		o += fmt.Sprintf("Depends: %s\n", info.Depends)
+	}
+	if info.Recommends != "" {
+		o += fmt.Sprintf("Recommends: %s\n", info.Recommends)
+	}
+	if info.Suggests != "" {
+		o += fmt.Sprintf("Suggests: %s\n", info.Suggests)
+	}
+	if info.Conflicts != "" {
+		o += fmt.Sprintf("Conflicts: %s\n", info.Conflicts)
+	}
+	if info.Provides != "" {
+		o += fmt.Sprintf("Provides: %s\n", info.Provides)
+	}
+	if info.Replaces != "" {
+		o += fmt.Sprintf("Replaces: %s\n", info.Replaces)
+	}
+	if info.Alternatives != "" {
+		o += fmt.Sprintf("Alternatives: %s\n", info.Alternatives)
+	}
+	if info.AutoInstalled {
+		o += "Auto-Installed: yes\n"
+	}
+	if info.Essential {
+		o += "Essential: yes\n"
+	}
+	for _, name := range sortedKeys(info.OEFields) {
+		o += fmt.Sprintf("%s: %s\n", name, info.OEFields[name])
+	}
+
+	o += fmt.Sprintf("Description: %s\n", info.DescrShort)
+	o += info.Descr
+
+	return o
+}
+
+// String renders the control file for this package, see RenderControl.
+func (c *control) String() string {
+	return RenderControl(c.info)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package ipk
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Test correct output of the IPK control file: no Installed-Size/Md5sum,
+// opkg extensions rendered in a stable order
+func TestControlFileOpkgFields(t *testing.T) {
+	controlExpect := `Package: foobar
+Version: 1.2.3
+Architecture: x86_64
+Maintainer: Jerry Jacobs <foo@bar.com>
+Depends: libc
+Alternatives: 100:/usr/bin/foo:/usr/bin/foo.foobar
+Auto-Installed: yes
+Essential: yes
+OE-Owner: yoe@example.com
+Description: Golang package for creating opkg packages
+`
+	pkg := New()
+	defer pkg.Close()
+
+	pkg.SetName("foobar")
+	pkg.SetVersion("1.2.3")
+	pkg.SetArchitecture(TranslateArchitecture("amd64"))
+	pkg.SetMaintainer("Jerry Jacobs")
+	pkg.SetMaintainerEmail("foo@bar.com")
+	pkg.SetDepends("libc")
+	pkg.SetAlternatives("100:/usr/bin/foo:/usr/bin/foo.foobar")
+	pkg.SetAutoInstalled(true)
+	pkg.SetEssential(true)
+	pkg.SetOEField("OE-Owner", "yoe@example.com")
+	pkg.SetShortDescription("Golang package for creating opkg packages")
+
+	control := pkg.control.String()
+	if control != controlExpect {
+		t.Error("Unexpected control file")
+		fmt.Printf("--- expected (len %d):\n'%s'\n--- got (len %d):\n'%s'---\n", len(controlExpect), controlExpect, len(control), control)
+	}
+}
+
+// Test dpkg -> opkg architecture Tuple translation
+func TestTranslateArchitecture(t *testing.T) {
+	cases := map[string]string{
+		"amd64":     "x86_64",
+		"arm64":     "aarch64_generic",
+		"mips_24kc": "mips_24kc", // unknown to the table, passed through unchanged
+	}
+	for in, want := range cases {
+		if got := TranslateArchitecture(in); got != want {
+			t.Errorf("TranslateArchitecture(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package ipk
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cjey/debpkg/internal/targzip"
+)
+
+// data holds the data.tar.gz payload. Unlike the dpkg data writer, opkg does
+// not consume a "md5sums" control member so no digests are tracked here.
+type data struct {
+	tgz  *targzip.TarGzip
+	dirs []string
+}
+
+func (d *data) addDirectory(dirpath string) error {
+	dirpath = filepath.Clean(dirpath)
+	if os.PathSeparator != '/' {
+		dirpath = strings.Replace(dirpath, string(os.PathSeparator), "/", -1)
+	}
+	d.addParentDirectories(dirpath)
+	for _, addedDir := range d.dirs {
+		if addedDir == dirpath {
+			return nil
+		}
+	}
+	if dirpath == "." {
+		return nil
+	}
+
+	if err := d.tgz.AddDirectory(dirpath); err != nil {
+		return err
+	}
+	d.dirs = append(d.dirs, dirpath)
+	return nil
+}
+
+func (d *data) addParentDirectories(filename string) {
+	dirname := filepath.Dir(filename)
+	if dirname == "." {
+		return
+	}
+	if os.PathSeparator != '/' {
+		dirname = strings.Replace(dirname, string(os.PathSeparator), "/", -1)
+	}
+	dirs := strings.Split(dirname, "/")
+	current := "/"
+	for _, dir := range dirs {
+		if len(dir) > 0 {
+			current += dir + "/"
+			d.addDirectory(current)
+		}
+	}
+}
+
+func (d *data) addFileString(contents, dest string) error {
+	d.addParentDirectories(dest)
+	return d.tgz.AddFileFromBuffer(dest, []byte(contents))
+}
+
+func (d *data) addFile(filename string, dest ...string) error {
+	var destfilename string
+
+	if len(dest) > 0 && len(dest[0]) > 0 {
+		destfilename = dest[0]
+	} else {
+		destfilename = filename
+	}
+
+	d.addParentDirectories(destfilename)
+
+	return d.tgz.AddFile(filename, dest...)
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package ipk implements creation of OPKG-compatible IPK packages, the
+// package format used by OpenWrt, Yocto and other embedded Linux
+// distributions.
+//
+// An IPK is, like a .deb, an ar(1) archive wrapping a debian-binary
+// member, a control.tar.gz and a data.tar.gz. The control-file keyword
+// set differs slightly from Debian's: Installed-Size and Md5sum are not
+// written, while Alternatives, Auto-Installed, Essential and the
+// Yocto-specific OE-* fields are supported.
+//
+// Overview
+//
+//  pkg := ipk.New()
+//
+//  pkg.SetName("foobar")
+//  pkg.SetVersion("1.2.3")
+//  pkg.SetArchitecture(ipk.TranslateArchitecture("arm64"))
+//  pkg.SetMaintainer("Foo Bar")
+//  pkg.SetMaintainerEmail("foo@bar.com")
+//
+//  pkg.SetShortDescription("Minimal foo bar package")
+//  pkg.SetDescription("Foo bar package doesn't do anything")
+//
+//  pkg.AddFile("/tmp/foobar")
+//
+//  pkg.Write("foobar.ipk")
+//  pkg.Close()
+package ipk
@@ -0,0 +1,223 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package ipk
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cjey/debpkg/internal/targzip"
+)
+
+// ErrClosed when the file I/O is requested and it is already closed
+var ErrClosed = errors.New("ipk: Closed")
+
+// ErrIO is returned when any file I/O failed
+var ErrIO = errors.New("ipk: I/O failed")
+
+// Package holds data for a single OPKG/IPK package, built with the same
+// SetName/SetVersion/AddFile API surface as debpkg.DebPkg.
+type Package struct {
+	control control
+	data    data
+	err     error
+}
+
+// New creates a new IPK package, optionally provide a tempdir to write
+// intermediate files, otherwise os.TempDir is used. A provided tempdir must
+// exist in order for it to work.
+func New(tempDir ...string) *Package {
+	pkg := &Package{}
+
+	dir := os.TempDir()
+	if len(tempDir) > 0 && len(tempDir[0]) > 0 {
+		dir = tempDir[0]
+	}
+
+	control, err := targzip.NewTempFile(dir)
+	if err != nil {
+		pkg.setError(ErrIO)
+		return pkg
+	}
+
+	data, err := targzip.NewTempFile(dir)
+	if err != nil {
+		control.Close()
+		control.Remove()
+		pkg.setError(ErrIO)
+		return pkg
+	}
+
+	pkg.control.tgz = control
+	pkg.data.tgz = data
+
+	return pkg
+}
+
+// setError sets the package error when not nil
+func (pkg *Package) setError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if pkg.err == ErrClosed {
+		panic("ipk: Trying to overwrite ErrClosed")
+	}
+	if err != ErrClosed {
+		pkg.err = err
+	}
+	return err
+}
+
+// Close closes the Package (and removes the intermediate files), rendering
+// it unusable for I/O. It returns an error, if any.
+func (pkg *Package) Close() error {
+	if pkg.err == ErrClosed {
+		return pkg.err
+	}
+	if pkg.control.tgz != nil {
+		pkg.control.tgz.Remove()
+	}
+	if pkg.data.tgz != nil {
+		pkg.data.tgz.Remove()
+	}
+	pkg.err = ErrClosed
+	return nil
+}
+
+// GetFilename calculates the filename based on name, version and architecture
+// SetName("foo"); SetVersion("1.33.7"); SetArchitecture("x86_64")
+// Generates filename "foo_1.33.7_x86_64.ipk"
+func (pkg *Package) GetFilename() string {
+	return fmt.Sprintf("%s_%s_%s.ipk",
+		pkg.control.info.Name,
+		pkg.control.info.Version,
+		pkg.control.info.Architecture)
+}
+
+// writeControlData writes the control.tar.gz and closes both tarballs
+func (pkg *Package) writeControlData() error {
+	if err := pkg.control.verify(); err != nil {
+		return err
+	}
+
+	controlFile := []byte(pkg.control.String())
+	if err := pkg.control.tgz.AddFileFromBuffer("control", controlFile); err != nil {
+		return fmt.Errorf("error while creating control.tar.gz: %s", err)
+	}
+
+	if err := pkg.control.tgz.Close(); err != nil {
+		return fmt.Errorf("cannot close tgz writer: %v", err)
+	}
+	if err := pkg.data.tgz.Close(); err != nil {
+		return fmt.Errorf("cannot close tgz writer: %v", err)
+	}
+	return nil
+}
+
+// Write writes the opkg-installable IPK package to filename
+func (pkg *Package) Write(filename string) error {
+	if pkg.err != nil {
+		return pkg.err
+	}
+	if err := pkg.writeControlData(); err != nil {
+		pkg.setError(err)
+		return err
+	}
+	if filename == "" {
+		filename = pkg.GetFilename()
+	}
+	err := pkg.createIpkAr(filename)
+	pkg.setError(err)
+	pkg.Close()
+	return err
+}
+
+// WriteFile renders controlContent into a temporary control.tar.gz and packs
+// it together with the already-finished data.tar.gz found at dataTarGzPath
+// into filename as an ar(1) IPK archive. tempDir, if non-empty, overrides
+// os.TempDir() for the intermediate control.tar.gz. It lets a sibling writer
+// that already has a data.tar.gz on disk, such as debpkg.DebPkg.WriteIPK,
+// produce an IPK without going through a Package at all.
+func WriteFile(filename, controlContent, dataTarGzPath string, tempDir ...string) error {
+	dir := os.TempDir()
+	if len(tempDir) > 0 && len(tempDir[0]) > 0 {
+		dir = tempDir[0]
+	}
+
+	control, err := targzip.NewTempFile(dir)
+	if err != nil {
+		return err
+	}
+	defer control.Remove()
+
+	if err := control.AddFileFromBuffer("control", []byte(controlContent)); err != nil {
+		return err
+	}
+	if err := control.Close(); err != nil {
+		return err
+	}
+
+	return WriteArchive(filename, control.Name(), dataTarGzPath)
+}
+
+// MarkConfigFile marks configuration files in the IPK package
+func (pkg *Package) MarkConfigFile(dest string) error {
+	if dest == "" {
+		return fmt.Errorf("config file cannot be empty")
+	}
+	return pkg.control.tgz.AddFileFromBuffer("conffiles", []byte(dest+"\n"))
+}
+
+// AddFile adds a file by filename to the package
+func (pkg *Package) AddFile(filename string, dest ...string) error {
+	if pkg.err != nil {
+		return pkg.err
+	}
+	return pkg.setError(pkg.data.addFile(filename, dest...))
+}
+
+// AddFileString adds a file to the package with the provided content
+func (pkg *Package) AddFileString(contents, dest string) error {
+	if pkg.err != nil {
+		return pkg.err
+	}
+	return pkg.setError(pkg.data.addFileString(contents, dest))
+}
+
+// AddEmptyDirectory adds an empty directory to the package
+func (pkg *Package) AddEmptyDirectory(dir string) error {
+	if pkg.err != nil {
+		return pkg.err
+	}
+	return pkg.setError(pkg.data.addDirectory(dir))
+}
+
+// AddDirectory adds a directory recursive to the package
+func (pkg *Package) AddDirectory(dir string) error {
+	if pkg.err != nil {
+		return pkg.err
+	}
+
+	pkg.data.addDirectory(dir)
+
+	return filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == "." || path == ".." || dir == path {
+			return nil
+		}
+		if f.IsDir() {
+			if err := pkg.data.addDirectory(path); err != nil {
+				return pkg.setError(err)
+			}
+			return pkg.AddDirectory(path)
+		}
+
+		return pkg.setError(pkg.AddFile(path))
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cjey/debpkg/internal/test"
+	"github.com/xor-gate/ar"
+)
+
+// dataTarGzMember reads the data.tar.gz ar(1) member out of a .deb or .ipk
+// file on disk.
+func dataTarGzMember(t *testing.T, filename string) []byte {
+	t.Helper()
+	return dataTarGzMemberNamed(t, filename, "data.tar.gz")
+}
+
+// dataTarGzMemberNamed reads the ar(1) member named member (e.g. "data.tar.gz"
+// or "data.tar.zst") out of a .deb or .ipk file on disk.
+func dataTarGzMemberNamed(t *testing.T, filename, member string) []byte {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := ar.NewReader(bytes.NewReader(b))
+	for {
+		hdr, err := r.Next()
+		if err != nil {
+			t.Fatalf("no %s member found in %s", member, filename)
+		}
+		if hdr.Name == member {
+			body, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return body
+		}
+	}
+}
+
+// Test that WriteIPK always emits a gzip-compressed data.tar.gz member, even
+// when SetCompression picked a codec opkg does not understand for the .deb
+// output.
+func TestWriteIPKForcesGzipRegardlessOfCompression(t *testing.T) {
+	deb := New()
+	defer deb.Close()
+
+	deb.SetName("ipk-compression-test")
+	deb.SetVersion("1.0.0")
+	deb.SetArchitecture("amd64")
+	deb.SetMaintainer("Debpkg Authors")
+	deb.SetMaintainerEmail("debpkg-authors@xor-gate.org")
+	deb.SetShortDescription("ipk compression test package")
+	deb.SetCompression(CompressionZstd)
+
+	srcFile, err := test.WriteTempFile(t.Name()+".txt", "hello ipk\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deb.AddFile(srcFile, "usr/share/ipk-compression-test/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	ipkFilename := test.TempDir() + "/" + t.Name() + ".ipk"
+	if err := deb.WriteIPK(ipkFilename); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := gzip.NewReader(bytes.NewReader(dataTarGzMember(t, ipkFilename))); err != nil {
+		t.Errorf("data.tar.gz member is not valid gzip: %v", err)
+	}
+}
+
+// Test that Write and WriteIPK can both be called on the same DebPkg
+// instance, building one .deb and one .ipk from the same AddFile data, with
+// each output rendered using its own compression.
+func TestWriteThenWriteIPKOnSameInstance(t *testing.T) {
+	deb := New()
+	defer deb.Close()
+
+	deb.SetName("dual-output-test")
+	deb.SetVersion("1.0.0")
+	deb.SetArchitecture("amd64")
+	deb.SetMaintainer("Debpkg Authors")
+	deb.SetMaintainerEmail("debpkg-authors@xor-gate.org")
+	deb.SetShortDescription("dual output test package")
+	deb.SetCompression(CompressionZstd)
+
+	srcFile, err := test.WriteTempFile(t.Name()+".txt", "hello dual output\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deb.AddFile(srcFile, "usr/share/dual-output-test/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	debFilename := test.TempDir() + "/" + t.Name() + ".deb"
+	if err := deb.Write(debFilename); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// The .deb was built with zstd, so its data.tar.zst member is named and
+	// compressed accordingly.
+	if got := dataTarMemberName(deb); got != "data.tar.zst" {
+		t.Errorf("dataTarMemberName() = %q, want %q", got, "data.tar.zst")
+	}
+
+	ipkFilename := test.TempDir() + "/" + t.Name() + ".ipk"
+	if err := deb.WriteIPK(ipkFilename); err != nil {
+		t.Fatalf("WriteIPK after Write: %v", err)
+	}
+
+	// WriteIPK forces gzip for its own data.tar.gz member regardless of the
+	// .deb's compression setting.
+	if _, err := gzip.NewReader(bytes.NewReader(dataTarGzMember(t, ipkFilename))); err != nil {
+		t.Errorf("ipk data.tar.gz member is not valid gzip: %v", err)
+	}
+}
+
+// Test the reverse call order of TestWriteThenWriteIPKOnSameInstance:
+// WriteIPK's forced gzip must not permanently downgrade the .deb's
+// compression when Write is called afterwards.
+func TestWriteIPKThenWriteOnSameInstance(t *testing.T) {
+	deb := New()
+	defer deb.Close()
+
+	deb.SetName("dual-output-reverse-test")
+	deb.SetVersion("1.0.0")
+	deb.SetArchitecture("amd64")
+	deb.SetMaintainer("Debpkg Authors")
+	deb.SetMaintainerEmail("debpkg-authors@xor-gate.org")
+	deb.SetShortDescription("dual output reverse test package")
+	deb.SetCompression(CompressionZstd)
+
+	srcFile, err := test.WriteTempFile(t.Name()+".txt", "hello dual output reverse\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deb.AddFile(srcFile, "usr/share/dual-output-reverse-test/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	ipkFilename := test.TempDir() + "/" + t.Name() + ".ipk"
+	if err := deb.WriteIPK(ipkFilename); err != nil {
+		t.Fatalf("WriteIPK: %v", err)
+	}
+	if _, err := gzip.NewReader(bytes.NewReader(dataTarGzMember(t, ipkFilename))); err != nil {
+		t.Errorf("ipk data.tar.gz member is not valid gzip: %v", err)
+	}
+
+	debFilename := test.TempDir() + "/" + t.Name() + ".deb"
+	if err := deb.Write(debFilename); err != nil {
+		t.Fatalf("Write after WriteIPK: %v", err)
+	}
+
+	// The explicit SetCompression(CompressionZstd) call must still be
+	// honoured for the .deb, even though WriteIPK ran first.
+	if got := dataTarMemberName(deb); got != "data.tar.zst" {
+		t.Errorf("dataTarMemberName() = %q, want %q", got, "data.tar.zst")
+	}
+	debMember := dataTarGzMemberNamed(t, debFilename, "data.tar.zst")
+	if len(debMember) < 4 || string(debMember[:4]) == "\x1f\x8b\x08\x00" {
+		t.Errorf(".deb data.tar.zst member looks like gzip, not zstd")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// SetBuildTime overrides the timestamp recorded for every entry of the
+// output package (the ar(1) member headers and every file/directory inside
+// control.tar and data.tar), regardless of the wall-clock time the package
+// is built at or the mtime of any file added via AddFile. Combined with the
+// lexical ordering Close already applies to tar entries, this makes Write
+// produce byte-identical output across repeated builds of the same inputs.
+//
+// New honours the SOURCE_DATE_EPOCH environment variable automatically (see
+// https://reproducible-builds.org/specs/source-date-epoch/); call
+// SetBuildTime explicitly to override it or to opt in without that
+// environment variable set. It must be called before any file is added via
+// AddFile/AddFileString/AddDirectory.
+func (deb *DebPkg) SetBuildTime(t time.Time) {
+	deb.buildTime = t
+	deb.hasBuildTime = true
+	deb.control.tgz.SetModTime(t)
+	deb.data.tgz.SetModTime(t)
+}
+
+// buildTimeOrNow returns the configured build time, or time.Now() if
+// SetBuildTime was never called and SOURCE_DATE_EPOCH wasn't set.
+func (deb *DebPkg) buildTimeOrNow() time.Time {
+	if deb.hasBuildTime {
+		return deb.buildTime
+	}
+	return time.Now()
+}
+
+// sourceDateEpoch parses the SOURCE_DATE_EPOCH environment variable, per
+// https://reproducible-builds.org/specs/source-date-epoch/
+func sourceDateEpoch() (time.Time, bool) {
+	v := os.Getenv("SOURCE_DATE_EPOCH")
+	if v == "" {
+		return time.Time{}, false
+	}
+	epoch, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(epoch, 0).UTC(), true
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cjey/debpkg/internal/test"
+)
+
+// Test that two packages built from the same inputs but at different
+// wall-clock times, and with their source files touched to different
+// mtimes, are byte-identical when SetBuildTime pins a fixed build time.
+func TestReproducibleBuildSHA256Identical(t *testing.T) {
+	buildTime := time.Unix(1700000000, 0).UTC()
+
+	build := func(name string, fileMTime time.Time) []byte {
+		deb := New()
+		defer deb.Close()
+
+		deb.SetName("reproducible-test")
+		deb.SetVersion("1.0.0")
+		deb.SetArchitecture("amd64")
+		deb.SetMaintainer("Debpkg Authors")
+		deb.SetMaintainerEmail("debpkg-authors@xor-gate.org")
+		deb.SetShortDescription("reproducible build test package")
+		deb.SetBuildTime(buildTime)
+
+		srcFile, err := test.WriteTempFile(name+".txt", "hello reproducible\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(srcFile, fileMTime, fileMTime); err != nil {
+			t.Fatal(err)
+		}
+		if err := deb.AddFile(srcFile, "usr/share/reproducible-test/hello.txt"); err != nil {
+			t.Fatal(err)
+		}
+
+		debFilename := test.TempDir() + "/" + name + ".deb"
+		if err := deb.Write(debFilename); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := ioutil.ReadFile(debFilename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	first := build(t.Name()+"-first", time.Unix(1600000000, 0))
+	time.Sleep(10 * time.Millisecond)
+	second := build(t.Name()+"-second", time.Unix(1800000000, 0))
+
+	firstSum := sha256.Sum256(first)
+	secondSum := sha256.Sum256(second)
+	if firstSum != secondSum {
+		t.Errorf("builds are not reproducible: sha256 %x != %x", firstSum, secondSum)
+	}
+}
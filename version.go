@@ -0,0 +1,146 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two Debian package version strings a and b as
+// `dpkg --compare-versions` would, returning a negative number if a sorts
+// before b, zero if they are equal, or a positive number if a sorts after b.
+//
+// Each version is split into epoch, upstream_version and debian_revision
+// exactly as dpkg does: an optional "epoch:" prefix, an optional
+// "-debian_revision" suffix (defaulting to "0" when absent), with everything
+// in between taken as the upstream_version. The epoch is compared
+// numerically, then upstream_version and debian_revision are each compared
+// with the tilde-aware alphanumeric segment comparison described in Debian
+// policy, where "~" sorts before anything else, including the end of a
+// segment.
+// See: https://www.debian.org/doc/debian-policy/ch-controlfields.html#s-f-Version
+func CompareVersions(a, b string) int {
+	aEpoch, aUpstream, aRevision := splitVersion(a)
+	bEpoch, bUpstream, bRevision := splitVersion(b)
+
+	if aEpoch != bEpoch {
+		if aEpoch < bEpoch {
+			return -1
+		}
+		return 1
+	}
+
+	if c := compareVersionSegment(aUpstream, bUpstream); c != 0 {
+		return c
+	}
+
+	return compareVersionSegment(aRevision, bRevision)
+}
+
+// splitVersion splits a version string into its epoch, upstream_version and
+// debian_revision components.
+func splitVersion(version string) (epoch uint64, upstream, revision string) {
+	if i := strings.IndexByte(version, ':'); i >= 0 {
+		epoch, _ = strconv.ParseUint(version[:i], 10, 64)
+		version = version[i+1:]
+	}
+
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		upstream, revision = version[:i], version[i+1:]
+	} else {
+		upstream, revision = version, "0"
+	}
+
+	return epoch, upstream, revision
+}
+
+// versionCharOrder returns the sort order of a single character within the
+// non-digit segments of a version string, matching dpkg's verrevcmp order():
+// "~" sorts lowest, then the end of a string and digits (both order 0, so a
+// run of digits always stops a non-digit comparison), then letters in their
+// natural order, then every other character above all letters.
+func versionCharOrder(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case c == 0 || isVersionDigit(c):
+		return 0
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+// compareVersionSegment compares two upstream_version or debian_revision
+// strings using dpkg's verrevcmp algorithm: it alternates between comparing
+// runs of non-digit characters (by versionCharOrder) and runs of digit
+// characters (numerically, ignoring leading zeros).
+func compareVersionSegment(a, b string) int {
+	for len(a) > 0 || len(b) > 0 {
+		// Compare the non-digit run each side is sitting on. Either side
+		// being exhausted or on a digit counts as "stop", exactly like
+		// dpkg's verrevcmp: order(0) == order(digit) == 0.
+		for (len(a) > 0 && !isVersionDigit(a[0])) || (len(b) > 0 && !isVersionDigit(b[0])) {
+			var ac, bc byte
+			if len(a) > 0 {
+				ac = a[0]
+			}
+			if len(b) > 0 {
+				bc = b[0]
+			}
+
+			aOrder, bOrder := versionCharOrder(ac), versionCharOrder(bc)
+			if aOrder != bOrder {
+				if aOrder < bOrder {
+					return -1
+				}
+				return 1
+			}
+			if len(a) > 0 {
+				a = a[1:]
+			}
+			if len(b) > 0 {
+				b = b[1:]
+			}
+		}
+
+		for len(a) > 0 && a[0] == '0' {
+			a = a[1:]
+		}
+		for len(b) > 0 && b[0] == '0' {
+			b = b[1:]
+		}
+
+		var aDigits, bDigits string
+		for len(a) > 0 && isVersionDigit(a[0]) {
+			aDigits += string(a[0])
+			a = a[1:]
+		}
+		for len(b) > 0 && isVersionDigit(b[0]) {
+			bDigits += string(b[0])
+			b = b[1:]
+		}
+		if len(aDigits) != len(bDigits) {
+			if len(aDigits) < len(bDigits) {
+				return -1
+			}
+			return 1
+		}
+		if aDigits != bDigits {
+			if aDigits < bDigits {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func isVersionDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
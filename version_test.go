@@ -0,0 +1,86 @@
+// Copyright 2017 Debpkg authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package debpkg
+
+import "testing"
+
+// Test correct ordering of version string rendering: [epoch:]upstream[~prerelease][+metadata][-release]
+func TestControlFileSetVersionStructuredComponents(t *testing.T) {
+	deb := New()
+	defer deb.Close()
+
+	deb.SetVersion("1.2.3")
+	deb.SetVersionEpoch(2)
+	deb.SetVersionPrerelease("rc1")
+	deb.SetVersionMetadata("git1234")
+	deb.SetVersionRelease("4")
+
+	versionExpect := "2:1.2.3~rc1+git1234-4"
+	if v := deb.control.version(); v != versionExpect {
+		t.Errorf("version() = %q, want %q", v, versionExpect)
+	}
+}
+
+// Test that GetFilename renders the version the same way the control file
+// does, for a package that only sets the structured SetVersion* fields
+// instead of the raw SetVersion string.
+func TestGetFilenameUsesStructuredVersion(t *testing.T) {
+	deb := New()
+	defer deb.Close()
+
+	deb.SetName("foo")
+	deb.SetArchitecture("amd64")
+	deb.SetVersionMajor(1)
+	deb.SetVersionMinor(2)
+	deb.SetVersionPatch(3)
+	deb.SetVersionRelease("4")
+
+	want := "foo-1.2.3-4_amd64.deb"
+	if got := deb.GetFilename(); got != want {
+		t.Errorf("GetFilename() = %q, want %q", got, want)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "1.0.1", -1},
+		{"1.0.1", "1.0.0", 1},
+		{"1:1.0.0", "2.0.0", 1},       // epoch outranks upstream version
+		{"1.0.0~rc1", "1.0.0", -1},    // tilde sorts before the release it precedes
+		{"1.0.0~rc1", "1.0.0~rc2", -1},
+		{"1.0.0-1", "1.0.0-2", -1},
+		{"1.0.0", "1.0.0-1", -1},      // missing debian_revision defaults to "0"
+		{"1.0.0+git1", "1.0.0", 1},    // "+" sorts after digits/letters
+		{"1.0~~", "1.0~~a", -1},       // tilde sorts before the end of string too
+		{"1.0", "1.0", 0},
+		{"r1250", "1300", 1},          // a leading letter sorts above a digit, not below
+		{"1.0-1~bpo9+1", "1.0-a", -1}, // digit beats letter at the same position
+		{"1.0rc1", "abc", -1},         // digit beats letter at the first position
+	}
+
+	for _, c := range cases {
+		if got := CompareVersions(c.a, c.b); sign(got) != c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want sign %d", c.a, c.b, got, c.want)
+		}
+		if got := CompareVersions(c.b, c.a); sign(got) != -c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want sign %d", c.b, c.a, got, -c.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}